@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errForbidden is returned by the built-in Authorizer helpers when a caller
+// is not allowed to invoke a method.
+var errForbidden = errors.New("auth: caller is not authorized to invoke this method")
+
+// Authorizer decides, once a caller has been authenticated, whether it may
+// invoke the given full gRPC method name (e.g. "/pkg.Service/Method"). It
+// runs after AuthenticatorFunc, so it can rely on whatever the authenticator
+// attached to the context (jwt.Claims, for instance).
+type Authorizer interface {
+	Authorize(ctx context.Context, method string) error
+}
+
+// AuthorizerFunc is an adapter to allow the use of ordinary functions as an Authorizer.
+type AuthorizerFunc func(ctx context.Context, method string) error
+
+// Authorize implements Authorizer.
+func (f AuthorizerFunc) Authorize(ctx context.Context, method string) error {
+	return f(ctx, method)
+}
+
+// AdminOnly returns an Authorizer that allows a method call only if isAdmin
+// reports true for the current context.
+func AdminOnly(isAdmin func(ctx context.Context) bool) Authorizer {
+	return AuthorizerFunc(func(ctx context.Context, method string) error {
+		if !isAdmin(ctx) {
+			return errForbidden
+		}
+		return nil
+	})
+}
+
+// SubjectMatches returns an Authorizer that allows a method call only if the
+// authenticated caller's subject, as reported by callerSubject, equals the
+// subject of the resource being accessed, as reported by resourceSubject.
+// Authorize runs before the handler and is only ever given ctx and the
+// method name, not the request message, so resourceSubject must derive the
+// resource's owner from something already in ctx (e.g. a path parameter
+// forwarded as gRPC metadata by the gateway) rather than the request body.
+func SubjectMatches(callerSubject, resourceSubject func(ctx context.Context) string) Authorizer {
+	return AuthorizerFunc(func(ctx context.Context, method string) error {
+		subject := callerSubject(ctx)
+		if subject == "" || subject != resourceSubject(ctx) {
+			return errForbidden
+		}
+		return nil
+	})
+}
+
+// AuthorizeUnaryInterceptor returns a grpc.UnaryServerInterceptor that rejects
+// the call with codes.PermissionDenied unless az.Authorize succeeds.
+func AuthorizeUnaryInterceptor(az Authorizer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := az.Authorize(ctx, info.FullMethod); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthorizeStreamInterceptor returns a grpc.StreamServerInterceptor that rejects
+// the call with codes.PermissionDenied unless az.Authorize succeeds.
+func AuthorizeStreamInterceptor(az Authorizer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := az.Authorize(ss.Context(), info.FullMethod); err != nil {
+			return status.Error(codes.PermissionDenied, err.Error())
+		}
+		return handler(srv, ss)
+	}
+}
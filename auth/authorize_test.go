@@ -0,0 +1,46 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pthethanh/micro/auth"
+)
+
+type testCtxKey string
+
+const (
+	adminCtxKey    testCtxKey = "admin"
+	callerCtxKey   testCtxKey = "caller"
+	resourceCtxKey testCtxKey = "resource"
+)
+
+func TestAdminOnly(t *testing.T) {
+	az := auth.AdminOnly(func(ctx context.Context) bool {
+		return ctx.Value(adminCtxKey) == true
+	})
+	admin := context.WithValue(context.Background(), adminCtxKey, true)
+	if err := az.Authorize(admin, "/pkg.Service/Method"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := az.Authorize(context.Background(), "/pkg.Service/Method"); err == nil {
+		t.Fatal("expected error for non-admin caller")
+	}
+}
+
+func TestSubjectMatches(t *testing.T) {
+	az := auth.SubjectMatches(
+		func(ctx context.Context) string { return ctx.Value(callerCtxKey).(string) },
+		func(ctx context.Context) string { return ctx.Value(resourceCtxKey).(string) },
+	)
+	ctx := context.WithValue(context.Background(), callerCtxKey, "u1")
+	ctx = context.WithValue(ctx, resourceCtxKey, "u1")
+	if err := az.Authorize(ctx, "/pkg.Service/Method"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other := context.WithValue(context.Background(), callerCtxKey, "u1")
+	other = context.WithValue(other, resourceCtxKey, "u2")
+	if err := az.Authorize(other, "/pkg.Service/Method"); err == nil {
+		t.Fatal("expected error when caller subject does not match resource subject")
+	}
+}
@@ -0,0 +1,39 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pthethanh/micro/auth"
+)
+
+// errForbidden is returned when the authenticated caller does not hold the
+// scopes required to invoke a method.
+var errForbidden = errors.New("jwt: caller does not have the required scopes")
+
+// MethodScopes is a declarative table of full gRPC method name
+// (e.g. "/pkg.Service/Method") to the scopes required to invoke it. Lookups
+// are an exact match on the method name only: methods absent from the table
+// are allowed through unchanged, and there is no wildcard entry. A
+// default-deny posture requires listing every method the caller may invoke.
+type MethodScopes map[string][]string
+
+// NewScopeAuthorizer returns an auth.Authorizer that requires the caller to
+// hold every scope configured for a method in required, using the jwt.Claims
+// previously attached to the context by Authenticator.
+func NewScopeAuthorizer(required MethodScopes) auth.Authorizer {
+	return auth.AuthorizerFunc(func(ctx context.Context, method string) error {
+		scopes, ok := required[method]
+		if !ok {
+			return nil
+		}
+		claims, ok := FromContext(ctx)
+		if !ok {
+			return errForbidden
+		}
+		if !claims.ContainScopes(scopes...) {
+			return errForbidden
+		}
+		return nil
+	})
+}
@@ -0,0 +1,25 @@
+package jwt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pthethanh/micro/auth/jwt"
+)
+
+func TestScopeAuthorizer(t *testing.T) {
+	az := jwt.NewScopeAuthorizer(jwt.MethodScopes{
+		"/pkg.Service/Method": {"read"},
+	})
+	ctx := jwt.NewContext(context.Background(), jwt.Claims{Scope: "read write"})
+	if err := az.Authorize(ctx, "/pkg.Service/Method"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := az.Authorize(ctx, "/pkg.Service/Other"); err != nil {
+		t.Fatalf("methods absent from the table must be allowed: %v", err)
+	}
+	noScope := jwt.NewContext(context.Background(), jwt.Claims{})
+	if err := az.Authorize(noScope, "/pkg.Service/Method"); err == nil {
+		t.Fatal("expected error for missing scope")
+	}
+}
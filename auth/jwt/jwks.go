@@ -0,0 +1,178 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// jwk is a single entry of a JSON Web Key Set, restricted to the subset
+// of fields needed to reconstruct an RSA public key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// jwksSource is a verify-only KeySource that fetches its keys from a remote
+// JWKS endpoint and periodically refreshes them in the background.
+type jwksSource struct {
+	url    string
+	client *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+
+	stop chan struct{}
+}
+
+// NewJWKSSource returns a KeySource that fetches keys from the JWKS document
+// at url (e.g. `https://issuer/.well-known/jwks.json`) and refreshes them
+// every `refresh` interval. Keys are cached in-memory between refreshes so
+// verification never blocks on a network call, and rotated keys become
+// available without a server restart.
+func NewJWKSSource(url string, refresh time.Duration) (KeySource, error) {
+	s := &jwksSource{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		keys:   map[string]*rsa.PublicKey{},
+		stop:   make(chan struct{}),
+	}
+	if err := s.fetch(); err != nil {
+		return nil, err
+	}
+	if refresh > 0 {
+		go s.refreshLoop(refresh)
+	}
+	return s, nil
+}
+
+func (s *jwksSource) refreshLoop(refresh time.Duration) {
+	t := time.NewTicker(refresh)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			// Best-effort: keep serving the last known good keys if a
+			// refresh fails, e.g. a transient network blip.
+			_ = s.fetch()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *jwksSource) fetch() error {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return fmt.Errorf("jwt: fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwt: fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+	var set jwks
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("jwt: decode jwks: %w", err)
+	}
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		pub, err := jwkToRSAPublicKey(k)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *jwksSource) Key(kid, alg string) (interface{}, error) {
+	switch jwt.GetSigningMethod(alg).(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+	default:
+		return nil, errWrongSigningMethod
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("jwt: no key found for kid %q", kid)
+	}
+	return key, nil
+}
+
+// Close stops the background refresh loop. It implements io.Closer so a
+// caller such as server.JWTAuthWithJWKS can hand off its lifecycle instead
+// of leaking the goroutine for the life of the process.
+func (s *jwksSource) Close() error {
+	close(s.stop)
+	return nil
+}
+
+func jwkToRSAPublicKey(k jwk) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: decode jwk n: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: decode jwk e: %w", err)
+	}
+	n := new(big.Int).SetBytes(nBytes)
+	e := new(big.Int).SetBytes(eBytes)
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+// oidcDiscovery is the subset of the OIDC discovery document
+// (`/.well-known/openid-configuration`) needed to locate the issuer's JWKS.
+type oidcDiscovery struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// discoverOIDC fetches the OIDC discovery document for issuer.
+func discoverOIDC(issuer string) (*oidcDiscovery, error) {
+	resp, err := http.Get(issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, fmt.Errorf("jwt: oidc discovery: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwt: oidc discovery: unexpected status %d", resp.StatusCode)
+	}
+	var d oidcDiscovery
+	if err := json.NewDecoder(resp.Body).Decode(&d); err != nil {
+		return nil, fmt.Errorf("jwt: oidc discovery: decode: %w", err)
+	}
+	return &d, nil
+}
+
+// NewJWKSSourceFromIssuer performs OIDC discovery against issuer to locate
+// its `jwks_uri` and returns a KeySource for it, refreshed every `refresh`
+// interval.
+func NewJWKSSourceFromIssuer(issuer string, refresh time.Duration) (KeySource, error) {
+	d, err := discoverOIDC(issuer)
+	if err != nil {
+		return nil, err
+	}
+	return NewJWKSSource(d.JWKSURI, refresh)
+}
@@ -0,0 +1,88 @@
+package jwt_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pthethanh/micro/auth/jwt"
+)
+
+func jwksHandler(t *testing.T, key *rsa.PrivateKey, kid string) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		set := map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": kid,
+					"alg": "RS256",
+					"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+				},
+			},
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}
+}
+
+func TestJWKSSource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(jwksHandler(t, key, "kid-1"))
+	defer srv.Close()
+
+	src, err := jwt.NewJWKSSource(srv.URL, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.(interface{ Close() error }).Close()
+
+	got, err := src.Key("kid-1", "RS256")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pub, ok := got.(*rsa.PublicKey); !ok || pub.N.Cmp(key.PublicKey.N) != 0 {
+		t.Fatalf("got key %v, want the published public key", got)
+	}
+	if _, err := src.Key("unknown-kid", "RS256"); err == nil {
+		t.Fatal("expected error for an unknown kid")
+	}
+	if _, err := src.Key("kid-1", "HS256"); err == nil {
+		t.Fatal("expected error for a non-RSA algorithm")
+	}
+}
+
+func TestJWKSSourceFromIssuer(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jwks.json", jwksHandler(t, key, "kid-1"))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   srv.URL,
+			"jwks_uri": srv.URL + "/jwks.json",
+		})
+	})
+
+	src, err := jwt.NewJWKSSourceFromIssuer(srv.URL, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.(interface{ Close() error }).Close()
+
+	if _, err := src.Key("kid-1", "RS256"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
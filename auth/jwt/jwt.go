@@ -18,11 +18,35 @@ var (
 	errAuthorizationMissing = errors.New("jwt: could not locate authorization metadata")
 	errMultipleAuthFound    = errors.New("jwt: too many authorization entries")
 	errInvalidToken         = errors.New("jwt: invalid token")
+	errNoSigningKey         = errors.New("jwt: key source does not provide a signing key")
 
 	// Lookup key for authorization metadata
 	authorizationMd = "authorization"
 )
 
+// KeySource resolves the key that must be used to verify a token, based on
+// the `kid` header and signing algorithm the token itself advertises.
+//
+// Implementations are provided for a static HMAC secret (NewStaticSecretSource),
+// RSA/ECDSA keys loaded from PEM files (NewPEMKeySource) and a remote JWKS
+// endpoint with periodic refresh (NewJWKSSource). This is what lets Authenticator,
+// Parse and Encode work with key rotation instead of a single shared secret.
+type KeySource interface {
+	// Key returns the key material to use to verify a token carrying the
+	// given `kid` and `alg` header values.
+	Key(kid, alg string) (interface{}, error)
+}
+
+// SigningKeySource is a KeySource that is also able to produce the key
+// currently used to sign new tokens, as used by Encode.
+type SigningKeySource interface {
+	KeySource
+
+	// SigningKey returns the kid, signing method and key that Encode should
+	// use to sign new tokens.
+	SigningKey() (kid string, method jwt.SigningMethod, key interface{}, err error)
+}
+
 // Claims represents the claims provided by the JWT.
 type Claims struct {
 	Scope     string `json:"scope,omitempty"`
@@ -59,14 +83,41 @@ func (c Claims) ContainScopes(scopes ...string) bool {
 	return true
 }
 
+// AuthenticatorOption customizes the validation performed by Authenticator
+// beyond the standard exp/nbf/iat checks already done by jwt.StandardClaims.
+type AuthenticatorOption func(*authenticatorOptions)
+
+type authenticatorOptions struct {
+	issuer   string
+	audience string
+}
+
+// WithIssuer requires the token `iss` claim to equal iss.
+func WithIssuer(iss string) AuthenticatorOption {
+	return func(opts *authenticatorOptions) {
+		opts.issuer = iss
+	}
+}
+
+// WithAudience requires the token `aud` claim to equal aud.
+func WithAudience(aud string) AuthenticatorOption {
+	return func(opts *authenticatorOptions) {
+		opts.audience = aud
+	}
+}
+
 // Authenticator returns an AuthenticatorFunc that
 // validates the provided JWT token in the :authorization header
-// of the metadata.
-func Authenticator(secret []byte) auth.AuthenticatorFunc {
+// of the metadata, resolving the verification key through src.
+func Authenticator(src KeySource, opts ...AuthenticatorOption) auth.AuthenticatorFunc {
+	o := &authenticatorOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
 	return func(ctx context.Context) (context.Context, error) {
 		var claims Claims
 		var newCtx context.Context
-		if err := ParseFromMetadata(ctx, secret, &claims); err != nil {
+		if err := parseFromMetadata(ctx, src, &claims, o); err != nil {
 			return newCtx, err
 		}
 		newCtx = NewContext(ctx, claims)
@@ -75,8 +126,12 @@ func Authenticator(secret []byte) auth.AuthenticatorFunc {
 }
 
 // ParseFromMetadata fetches the JWT from the :authorization metadata located
-// in the `Context`, validates the JWT and extracts the Claims.
-func ParseFromMetadata(ctx context.Context, secret []byte, c jwt.Claims) error {
+// in the `Context`, validates the JWT using src and extracts the Claims.
+func ParseFromMetadata(ctx context.Context, src KeySource, c jwt.Claims) error {
+	return parseFromMetadata(ctx, src, c, &authenticatorOptions{})
+}
+
+func parseFromMetadata(ctx context.Context, src KeySource, c jwt.Claims, o *authenticatorOptions) error {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return errMetadataMissing
@@ -88,27 +143,45 @@ func ParseFromMetadata(ctx context.Context, secret []byte, c jwt.Claims) error {
 	if len(slice) > 1 {
 		return errMultipleAuthFound
 	}
-	return Parse(slice[0], secret, c)
+	return parse(slice[0], src, c, o)
 }
 
-// Parse and validate a JWT string.
-func Parse(t string, s []byte, c jwt.Claims) error {
+// Parse and validate a JWT string, resolving the verification key through src.
+func Parse(t string, src KeySource, c jwt.Claims) error {
+	return parse(t, src, c, &authenticatorOptions{})
+}
+
+func parse(t string, src KeySource, c jwt.Claims, o *authenticatorOptions) error {
 	_, err := jwt.ParseWithClaims(t, c, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errInvalidToken
-		}
-		return s, nil
+		kid, _ := token.Header["kid"].(string)
+		return src.Key(kid, token.Method.Alg())
 	})
 	if err != nil {
 		return errInvalidToken
 	}
+	if claims, ok := c.(*Claims); ok {
+		if o.issuer != "" && !claims.VerifyIssuer(o.issuer, true) {
+			return errInvalidToken
+		}
+		if o.audience != "" && !claims.VerifyAudience(o.audience, true) {
+			return errInvalidToken
+		}
+	}
 	return c.Valid()
 }
 
-// Encode encodes the jwt Claim to a JWT string.
-func Encode(c jwt.Claims, secret []byte) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, c)
-	return token.SignedString(secret)
+// Encode encodes the jwt Claim to a JWT string, signed with the
+// current signing key returned by src.
+func Encode(c jwt.Claims, src SigningKeySource) (string, error) {
+	kid, method, key, err := src.SigningKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(method, c)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	return token.SignedString(key)
 }
 
 // The context key
@@ -0,0 +1,25 @@
+package jwt_test
+
+import (
+	"testing"
+
+	"github.com/pthethanh/micro/auth/jwt"
+)
+
+func TestEncodeParseStaticSecretSource(t *testing.T) {
+	src := jwt.NewStaticSecretSource([]byte("secret"))
+	want := jwt.Claims{
+		UserID: "u1",
+	}
+	token, err := jwt.Encode(want, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := jwt.Claims{}
+	if err := jwt.Parse(token, src, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.UserID != want.UserID {
+		t.Fatalf("got user_id=%s, want user_id=%s", got.UserID, want.UserID)
+	}
+}
@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// errWrongSigningMethod is returned when a token uses an algorithm that is
+// not supported by the key source it is being verified against.
+var errWrongSigningMethod = errors.New("jwt: unexpected signing method")
+
+// staticSecretSource is a KeySource backed by a single, in-memory HMAC
+// secret. It reproduces the pre-KeySource behavior of this package.
+type staticSecretSource struct {
+	secret []byte
+}
+
+// NewStaticSecretSource returns a KeySource that always verifies and signs
+// using the given shared HMAC secret.
+func NewStaticSecretSource(secret []byte) SigningKeySource {
+	return &staticSecretSource{secret: secret}
+}
+
+func (s *staticSecretSource) Key(kid, alg string) (interface{}, error) {
+	if _, ok := jwt.GetSigningMethod(alg).(*jwt.SigningMethodHMAC); !ok {
+		return nil, errWrongSigningMethod
+	}
+	return s.secret, nil
+}
+
+func (s *staticSecretSource) SigningKey() (string, jwt.SigningMethod, interface{}, error) {
+	return "", jwt.SigningMethodHS256, s.secret, nil
+}
+
+// pemKeySource is a KeySource backed by a single RSA or ECDSA
+// public/private key pair loaded from PEM files on disk. It can sign only
+// when a private key was loaded.
+type pemKeySource struct {
+	method     jwt.SigningMethod
+	publicKey  interface{}
+	privateKey interface{}
+	kid        string
+}
+
+// NewPEMKeySource loads an RSA or ECDSA key pair from PEM encoded files and
+// returns a KeySource that verifies tokens using the public key. privateKeyPath
+// may be empty if the source is only ever used to verify, never to sign.
+// kid is advertised on tokens signed through this source and is optional.
+func NewPEMKeySource(method jwt.SigningMethod, publicKeyPath, privateKeyPath, kid string) (SigningKeySource, error) {
+	pubBytes, err := ioutil.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: read public key: %w", err)
+	}
+	src := &pemKeySource{method: method, kid: kid}
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		pub, err := jwt.ParseRSAPublicKeyFromPEM(pubBytes)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: parse rsa public key: %w", err)
+		}
+		src.publicKey = pub
+	case *jwt.SigningMethodECDSA:
+		pub, err := jwt.ParseECPublicKeyFromPEM(pubBytes)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: parse ecdsa public key: %w", err)
+		}
+		src.publicKey = pub
+	default:
+		return nil, fmt.Errorf("jwt: unsupported signing method %s", method.Alg())
+	}
+	if privateKeyPath == "" {
+		return src, nil
+	}
+	privBytes, err := ioutil.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: read private key: %w", err)
+	}
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		priv, err := jwt.ParseRSAPrivateKeyFromPEM(privBytes)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: parse rsa private key: %w", err)
+		}
+		src.privateKey = priv
+	case *jwt.SigningMethodECDSA:
+		priv, err := jwt.ParseECPrivateKeyFromPEM(privBytes)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: parse ecdsa private key: %w", err)
+		}
+		src.privateKey = priv
+	}
+	return src, nil
+}
+
+func (s *pemKeySource) Key(kid, alg string) (interface{}, error) {
+	if alg != s.method.Alg() {
+		return nil, errWrongSigningMethod
+	}
+	return s.publicKey, nil
+}
+
+func (s *pemKeySource) SigningKey() (string, jwt.SigningMethod, interface{}, error) {
+	if s.privateKey == nil {
+		return "", nil, nil, errNoSigningKey
+	}
+	return s.kid, s.method, s.privateKey, nil
+}
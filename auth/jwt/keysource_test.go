@@ -0,0 +1,73 @@
+package jwt_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+
+	"github.com/pthethanh/micro/auth/jwt"
+)
+
+func writePEMKeyPair(t *testing.T) (pubPath, privPath string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+
+	pubBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubPath = filepath.Join(dir, "pub.pem")
+	if err := os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	privPath = filepath.Join(dir, "priv.pem")
+	privBytes := x509.MarshalPKCS1PrivateKey(key)
+	if err := os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: privBytes}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return pubPath, privPath
+}
+
+func TestEncodeParsePEMKeySource(t *testing.T) {
+	pubPath, privPath := writePEMKeyPair(t)
+
+	src, err := jwt.NewPEMKeySource(jwtgo.SigningMethodRS256, pubPath, privPath, "kid-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := jwt.Claims{UserID: "u1"}
+	token, err := jwt.Encode(want, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := jwt.Claims{}
+	if err := jwt.Parse(token, src, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.UserID != want.UserID {
+		t.Fatalf("got user_id=%s, want user_id=%s", got.UserID, want.UserID)
+	}
+}
+
+func TestPEMKeySourceVerifyOnly(t *testing.T) {
+	pubPath, _ := writePEMKeyPair(t)
+
+	src, err := jwt.NewPEMKeySource(jwtgo.SigningMethodRS256, pubPath, "", "kid-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := src.SigningKey(); err == nil {
+		t.Fatal("expected error signing with a verify-only source")
+	}
+}
@@ -0,0 +1,65 @@
+// Package opa implements an auth.Authorizer backed by an Open Policy Agent
+// (OPA) Rego policy, letting services externalize per-RPC access rules
+// instead of hard-coding them in handler or table-driven Go code.
+package opa
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-policy-agent/opa/rego"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/pthethanh/micro/auth"
+	"github.com/pthethanh/micro/auth/jwt"
+)
+
+// Authorizer evaluates a compiled Rego query against a
+// `{claims, method, metadata}` input document to decide whether a call is
+// authorized. It implements auth.Authorizer.
+type Authorizer struct {
+	query rego.PreparedEvalQuery
+}
+
+// New compiles the Rego policy at policyPath and returns an Authorizer that
+// evaluates query (defaulting to "data.micro.authz.allow") against the
+// current call. The rule referenced by query must evaluate to a boolean.
+func New(ctx context.Context, policyPath string, query string) (*Authorizer, error) {
+	if query == "" {
+		query = "data.micro.authz.allow"
+	}
+	pq, err := rego.New(
+		rego.Query(query),
+		rego.Load([]string{policyPath}, nil),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("opa: prepare policy %s: %w", policyPath, err)
+	}
+	return &Authorizer{query: pq}, nil
+}
+
+// Authorize implements auth.Authorizer.
+func (a *Authorizer) Authorize(ctx context.Context, method string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	input := map[string]interface{}{
+		"method":   method,
+		"metadata": map[string][]string(md),
+	}
+	if claims, ok := jwt.FromContext(ctx); ok {
+		input["claims"] = claims
+	}
+	rs, err := a.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return fmt.Errorf("opa: eval policy: %w", err)
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return fmt.Errorf("auth: denied by policy")
+	}
+	allow, _ := rs[0].Expressions[0].Value.(bool)
+	if !allow {
+		return fmt.Errorf("auth: denied by policy")
+	}
+	return nil
+}
+
+var _ auth.Authorizer = (*Authorizer)(nil)
@@ -0,0 +1,50 @@
+package opa_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pthethanh/micro/auth/jwt"
+	"github.com/pthethanh/micro/auth/opa"
+)
+
+const policy = `
+package micro.authz
+
+default allow = false
+
+allow {
+	input.method == "/pkg.Service/Method"
+	input.claims.scope == "read"
+}
+`
+
+func writePolicy(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.rego")
+	if err := os.WriteFile(path, []byte(policy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAuthorizer(t *testing.T) {
+	az, err := opa.New(context.Background(), writePolicy(t), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowed := jwt.NewContext(context.Background(), jwt.Claims{Scope: "read"})
+	if err := az.Authorize(allowed, "/pkg.Service/Method"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	denied := jwt.NewContext(context.Background(), jwt.Claims{Scope: "write"})
+	if err := az.Authorize(denied, "/pkg.Service/Method"); err == nil {
+		t.Fatal("expected error for caller without the required scope")
+	}
+	if err := az.Authorize(allowed, "/pkg.Service/Other"); err == nil {
+		t.Fatal("expected error for a method the policy does not allow")
+	}
+}
@@ -0,0 +1,47 @@
+// Package broker defines the publish/subscribe abstraction used throughout
+// the framework, so services can swap an in-process broker for NATS, Kafka
+// or any other implementation without changing handler code. See
+// broker/memory, broker/nats and broker/kafka for implementations, and
+// FromEnv to pick one at runtime.
+package broker
+
+// Message is the payload exchanged through a Broker. Header carries
+// arbitrary string metadata alongside the raw Body. ID, Source, Type,
+// SpecVersion, Subject, Time and DataContentType are the CloudEvents v1.0
+// context attributes (https://github.com/cloudevents/spec) as first-class
+// fields, so any producer can publish a CloudEvent-aware Message without
+// going through the broker/cloudevents package; they are left zero for a
+// plain, non-CE message. Time is RFC3339Nano encoded, matching the
+// CloudEvents HTTP binding.
+type Message struct {
+	Header map[string]string `json:"header,omitempty"`
+	Body   []byte            `json:"body,omitempty"`
+
+	ID              string `json:"id,omitempty"`
+	Source          string `json:"source,omitempty"`
+	Type            string `json:"type,omitempty"`
+	SpecVersion     string `json:"specversion,omitempty"`
+	Subject         string `json:"subject,omitempty"`
+	Time            string `json:"time,omitempty"`
+	DataContentType string `json:"datacontenttype,omitempty"`
+}
+
+// Event is a Message delivered to a Subscribe handler, together with the
+// topic it was published on.
+type Event interface {
+	Topic() string
+	Message() *Message
+}
+
+// Subscription represents an active Subscribe call. Unsubscribe stops
+// delivery and releases any resources held by the subscription.
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Broker publishes and subscribes Messages on named topics.
+type Broker interface {
+	Publish(topic string, m *Message) error
+	Subscribe(topic string, h func(Event) error) (Subscription, error)
+	Close() error
+}
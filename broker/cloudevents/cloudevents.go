@@ -0,0 +1,168 @@
+// Package cloudevents maps broker.Message to and from the CloudEvents v1.0
+// spec (https://github.com/cloudevents/spec), so services can interop with
+// Knative Eventing and other CE producers/consumers without hand-rolling
+// headers. CE attributes live directly on broker.Message's own fields, so
+// any broker.Broker implementation carries them natively.
+package cloudevents
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pthethanh/micro/broker"
+)
+
+// Binary-mode header names, as defined by the CloudEvents HTTP protocol binding.
+const (
+	HeaderID              = "ce-id"
+	HeaderSource          = "ce-source"
+	HeaderType            = "ce-type"
+	HeaderSpecVersion     = "ce-specversion"
+	HeaderSubject         = "ce-subject"
+	HeaderTime            = "ce-time"
+	HeaderDataContentType = "content-type"
+
+	// SpecVersion is the CloudEvents spec version this package implements.
+	SpecVersion = "1.0"
+)
+
+// Event is a CloudEvents v1.0 envelope.
+type Event struct {
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	SpecVersion     string    `json:"specversion"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time,omitempty"`
+	DataContentType string    `json:"datacontenttype,omitempty"`
+	Data            []byte    `json:"data,omitempty"`
+}
+
+// ToMessage maps e onto a broker.Message, setting the CE attributes on
+// Message's own fields and Data as the message Body.
+func (e Event) ToMessage() *broker.Message {
+	if e.SpecVersion == "" {
+		e.SpecVersion = SpecVersion
+	}
+	m := &broker.Message{
+		Body:            e.Data,
+		ID:              e.ID,
+		Source:          e.Source,
+		Type:            e.Type,
+		SpecVersion:     e.SpecVersion,
+		Subject:         e.Subject,
+		DataContentType: e.DataContentType,
+	}
+	if !e.Time.IsZero() {
+		m.Time = e.Time.Format(time.RFC3339Nano)
+	}
+	return m
+}
+
+// FromMessage reconstructs the Event carried by m's CE fields.
+func FromMessage(m *broker.Message) (Event, error) {
+	e := Event{
+		ID:              m.ID,
+		Source:          m.Source,
+		Type:            m.Type,
+		SpecVersion:     m.SpecVersion,
+		Subject:         m.Subject,
+		DataContentType: m.DataContentType,
+		Data:            m.Body,
+	}
+	if m.Time != "" {
+		t, err := time.Parse(time.RFC3339Nano, m.Time)
+		if err != nil {
+			return Event{}, fmt.Errorf("cloudevents: parse time: %w", err)
+		}
+		e.Time = t
+	}
+	if e.SpecVersion == "" {
+		return Event{}, fmt.Errorf("cloudevents: message carries no %s attribute", HeaderSpecVersion)
+	}
+	return e, nil
+}
+
+// FromEvent decodes the broker.Event previously published from a CloudEvent
+// back into a typed Event, for use on the subscriber side.
+func FromEvent(ev broker.Event) (Event, error) {
+	return FromMessage(ev.Message())
+}
+
+// structuredEvent is the JSON shape of a CloudEvents structured-mode
+// document, where all attributes and the data live in a single object.
+type structuredEvent struct {
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	SpecVersion     string          `json:"specversion"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            *time.Time      `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+	// DataBase64 carries Data for events whose payload isn't itself valid
+	// JSON, per the CloudEvents v1.0 JSON format spec's `data_base64`.
+	DataBase64 string `json:"data_base64,omitempty"`
+}
+
+// marshalStructured encodes e as a structured-mode CloudEvents JSON document.
+// Data is embedded as-is when it's valid JSON, and base64 encoded into
+// data_base64 otherwise, per the CloudEvents v1.0 JSON format spec.
+func marshalStructured(e Event) ([]byte, error) {
+	s := structuredEvent{
+		ID:              e.ID,
+		Source:          e.Source,
+		Type:            e.Type,
+		SpecVersion:     e.SpecVersion,
+		Subject:         e.Subject,
+		DataContentType: e.DataContentType,
+	}
+	if s.SpecVersion == "" {
+		s.SpecVersion = SpecVersion
+	}
+	if !e.Time.IsZero() {
+		t := e.Time
+		s.Time = &t
+	}
+	if len(e.Data) > 0 {
+		if json.Valid(e.Data) {
+			s.Data = e.Data
+		} else {
+			s.DataBase64 = base64.StdEncoding.EncodeToString(e.Data)
+		}
+	}
+	return json.Marshal(s)
+}
+
+// unmarshalStructured decodes a structured-mode CloudEvents JSON document,
+// preferring data_base64 over data if both are somehow present.
+func unmarshalStructured(data []byte) (Event, error) {
+	var s structuredEvent
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Event{}, fmt.Errorf("cloudevents: decode structured event: %w", err)
+	}
+	e := Event{
+		ID:              s.ID,
+		Source:          s.Source,
+		Type:            s.Type,
+		SpecVersion:     s.SpecVersion,
+		Subject:         s.Subject,
+		DataContentType: s.DataContentType,
+	}
+	switch {
+	case s.DataBase64 != "":
+		raw, err := base64.StdEncoding.DecodeString(s.DataBase64)
+		if err != nil {
+			return Event{}, fmt.Errorf("cloudevents: decode data_base64: %w", err)
+		}
+		e.Data = raw
+	case len(s.Data) > 0:
+		e.Data = []byte(s.Data)
+	}
+	if s.Time != nil {
+		e.Time = *s.Time
+	}
+	return e, nil
+}
@@ -0,0 +1,73 @@
+package cloudevents_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pthethanh/micro/broker/cloudevents"
+)
+
+func TestMessageRoundTrip(t *testing.T) {
+	want := cloudevents.Event{
+		ID:              "1",
+		Source:          "/test",
+		Type:            "test.event",
+		SpecVersion:     cloudevents.SpecVersion,
+		DataContentType: "application/json",
+		Data:            []byte(`{"ok":true}`),
+	}
+	got, err := cloudevents.FromMessage(want.ToMessage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != want.ID || got.Source != want.Source || got.Type != want.Type {
+		t.Fatalf("got=%+v, want=%+v", got, want)
+	}
+	if !bytes.Equal(got.Data, want.Data) {
+		t.Fatalf("got data=%s, want data=%s", got.Data, want.Data)
+	}
+}
+
+func TestStructuredModeNonJSONData(t *testing.T) {
+	want := cloudevents.Event{
+		ID:              "1",
+		Source:          "/test",
+		Type:            "test.event",
+		SpecVersion:     cloudevents.SpecVersion,
+		DataContentType: "application/octet-stream",
+		Data:            []byte{0x00, 0x01, 0xff, 'n', 'o', 't', ' ', 'j', 's', 'o', 'n'},
+	}
+	rec := httptest.NewRecorder()
+	if err := cloudevents.WriteHTTP(rec, want); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/events", rec.Body)
+	req.Header.Set("Content-Type", rec.Header().Get("Content-Type"))
+	got, err := cloudevents.DecodeHTTPRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Data, want.Data) {
+		t.Fatalf("got data=%v, want data=%v", got.Data, want.Data)
+	}
+}
+
+func TestDecodeHTTPRequestBinaryMode(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader([]byte(`{"ok":true}`)))
+	req.Header.Set("Ce-Id", "1")
+	req.Header.Set("Ce-Source", "/test")
+	req.Header.Set("Ce-Type", "test.event")
+	req.Header.Set("Ce-Specversion", cloudevents.SpecVersion)
+	req.Header.Set("Content-Type", "application/json")
+
+	e, err := cloudevents.DecodeHTTPRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.ID != "1" || e.Source != "/test" || e.Type != "test.event" {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+}
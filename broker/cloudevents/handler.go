@@ -0,0 +1,27 @@
+package cloudevents
+
+import (
+	"net/http"
+
+	"github.com/pthethanh/micro/broker"
+)
+
+// HTTPHandler returns an http.Handler that decodes a POSTed CloudEvent and
+// republishes it onto b under every topic in topics.
+func HTTPHandler(b broker.Broker, topics ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e, err := DecodeHTTPRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m := e.ToMessage()
+		for _, topic := range topics {
+			if err := b.Publish(topic, m); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
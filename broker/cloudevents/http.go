@@ -0,0 +1,64 @@
+package cloudevents
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const structuredContentType = "application/cloudevents+json"
+
+// DecodeHTTPRequest decodes a POSTed CloudEvent from r, supporting both the
+// binary content mode (attributes in `Ce-*` headers, data in the body) and
+// the structured content mode (`Content-Type: application/cloudevents+json`,
+// the whole event as one JSON document).
+func DecodeHTTPRequest(r *http.Request) (Event, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return Event{}, fmt.Errorf("cloudevents: read request body: %w", err)
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), structuredContentType) {
+		return unmarshalStructured(body)
+	}
+	m := make(map[string]string, len(r.Header))
+	for key := range r.Header {
+		m[strings.ToLower(key)] = r.Header.Get(key)
+	}
+	e := Event{
+		ID:              m[HeaderID],
+		Source:          m[HeaderSource],
+		Type:            m[HeaderType],
+		SpecVersion:     m[HeaderSpecVersion],
+		Subject:         m[HeaderSubject],
+		DataContentType: m[HeaderDataContentType],
+		Data:            body,
+	}
+	if e.DataContentType == "" {
+		e.DataContentType = r.Header.Get("Content-Type")
+	}
+	if e.SpecVersion == "" {
+		return Event{}, fmt.Errorf("cloudevents: missing %s header", HeaderSpecVersion)
+	}
+	if raw, ok := m[HeaderTime]; ok && raw != "" {
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return Event{}, fmt.Errorf("cloudevents: parse time: %w", err)
+		}
+		e.Time = t
+	}
+	return e, nil
+}
+
+// WriteHTTP writes e to w in structured content mode. It is mainly useful
+// for tests and for services that need to forward a CloudEvent over HTTP.
+func WriteHTTP(w http.ResponseWriter, e Event) error {
+	data, err := marshalStructured(e)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", structuredContentType)
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,47 @@
+package broker
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// brokerKey is the context key used to attach a Broker to a context.
+type brokerKey struct{}
+
+// NewContext returns a new Context that carries b.
+func NewContext(ctx context.Context, b Broker) context.Context {
+	return context.WithValue(ctx, brokerKey{}, b)
+}
+
+// FromContext returns the Broker previously attached to ctx by NewContext.
+func FromContext(ctx context.Context) (Broker, bool) {
+	b, ok := ctx.Value(brokerKey{}).(Broker)
+	return b, ok
+}
+
+// UnaryInterceptor returns a grpc.UnaryServerInterceptor that attaches b to
+// the context of every unary call, so handlers can retrieve it via FromContext.
+func UnaryInterceptor(b Broker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(NewContext(ctx, b), req)
+	}
+}
+
+// StreamInterceptor returns a grpc.StreamServerInterceptor that attaches b to
+// the context of every streaming call, so handlers can retrieve it via FromContext.
+func StreamInterceptor(b Broker) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: NewContext(ss.Context(), b)})
+	}
+}
+
+// contextServerStream wraps a grpc.ServerStream to override its Context.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,154 @@
+// Package kafka implements broker.Broker on top of Kafka consumer groups,
+// giving durable, at-least-once delivery across multiple subscribers and
+// automatic broker reconnect/backoff via the underlying client.
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/pthethanh/micro/broker"
+)
+
+func init() {
+	broker.Register("kafka", func() (broker.Broker, error) {
+		addrs := strings.Split(os.Getenv("KAFKA_BROKERS"), ",")
+		return New(addrs, Group(os.Getenv("KAFKA_CONSUMER_GROUP")))
+	})
+}
+
+// Broker is a broker.Broker backed by Kafka, using sarama consumer groups.
+type Broker struct {
+	addrs    []string
+	group    string
+	config   *sarama.Config
+	producer sarama.SyncProducer
+}
+
+// Option customizes a Broker returned by New.
+type Option func(*Broker)
+
+// Group sets the durable consumer group name joined by every Subscribe call
+// on the Broker, so subscribers sharing a group load-balance a topic's
+// partitions between them instead of each receiving every message.
+func Group(name string) Option {
+	return func(b *Broker) {
+		if name != "" {
+			b.group = name
+		}
+	}
+}
+
+// New connects to the Kafka brokers at addrs and returns a Broker.
+func New(addrs []string, opts ...Option) (*Broker, error) {
+	b := &Broker{addrs: addrs, group: "micro"}
+	for _, opt := range opts {
+		opt(b)
+	}
+	cfg := sarama.NewConfig()
+	cfg.Producer.Return.Successes = true
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Producer.Retry.Max = 5
+	cfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	cfg.Metadata.Retry.Backoff = 2 * time.Second
+	b.config = cfg
+	p, err := sarama.NewSyncProducer(addrs, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: new producer: %w", err)
+	}
+	b.producer = p
+	return b, nil
+}
+
+// Publish implements broker.Broker.
+func (b *Broker) Publish(topic string, m *broker.Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("kafka: encode message: %w", err)
+	}
+	if _, _, err := b.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.ByteEncoder(data),
+	}); err != nil {
+		return fmt.Errorf("kafka: publish: %w", err)
+	}
+	return nil
+}
+
+// Subscribe implements broker.Broker. It joins the Broker's durable
+// consumer group for topic and keeps rejoining it whenever the group session
+// ends, e.g. during a rebalance, a transient broker error or a handler error,
+// waiting a short backoff first when Consume returned an error.
+func (b *Broker) Subscribe(topic string, h func(broker.Event) error) (broker.Subscription, error) {
+	group, err := sarama.NewConsumerGroup(b.addrs, b.group, b.config)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: new consumer group: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := &consumerHandler{topic: topic, h: h}
+	go func() {
+		for ctx.Err() == nil {
+			if err := group.Consume(ctx, []string{topic}, handler); err != nil {
+				time.Sleep(2 * time.Second)
+			}
+		}
+	}()
+	return &subscription{group: group, cancel: cancel}, nil
+}
+
+// Close closes the underlying Kafka producer.
+func (b *Broker) Close() error {
+	return b.producer.Close()
+}
+
+type consumerHandler struct {
+	topic string
+	h     func(broker.Event) error
+}
+
+func (*consumerHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (*consumerHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+func (c *consumerHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		m := &broker.Message{}
+		if err := json.Unmarshal(msg.Value, m); err != nil {
+			sess.MarkMessage(msg, "") // malformed payload, do not keep redelivering it
+			continue
+		}
+		if err := c.h(&event{topic: c.topic, msg: m}); err != nil {
+			// Stop consuming the claim without marking the offset: this ends
+			// the group session, and Subscribe rejoins the group from the last
+			// committed offset, so the message is redelivered, giving
+			// at-least-once delivery. Marking a later message instead would
+			// commit past this one.
+			return err
+		}
+		sess.MarkMessage(msg, "")
+	}
+	return nil
+}
+
+type event struct {
+	topic string
+	msg   *broker.Message
+}
+
+func (e *event) Topic() string            { return e.topic }
+func (e *event) Message() *broker.Message { return e.msg }
+
+type subscription struct {
+	group  sarama.ConsumerGroup
+	cancel context.CancelFunc
+}
+
+func (s *subscription) Unsubscribe() error {
+	s.cancel()
+	return s.group.Close()
+}
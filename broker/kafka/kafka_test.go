@@ -0,0 +1,84 @@
+package kafka
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/pthethanh/micro/broker"
+)
+
+func TestGroupEmptyNamePreservesDefault(t *testing.T) {
+	b := &Broker{group: "micro"}
+	Group("")(b)
+	if b.group != "micro" {
+		t.Fatalf("got group=%q, want the default %q to be preserved", b.group, "micro")
+	}
+	Group("custom")(b)
+	if b.group != "custom" {
+		t.Fatalf("got group=%q, want group=%q", b.group, "custom")
+	}
+}
+
+// fakeSession is a sarama.ConsumerGroupSession that only records the offsets
+// marked on it, enough to exercise consumerHandler.ConsumeClaim's
+// at-least-once semantics without a real Kafka broker.
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	marked []int64
+}
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.marked = append(s.marked, msg.Offset)
+}
+
+// fakeClaim is a sarama.ConsumerGroupClaim backed by a closeable channel of
+// messages, standing in for a partition claim.
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+func TestConsumerHandlerConsumeClaim(t *testing.T) {
+	var handled []string
+	h := &consumerHandler{
+		topic: "test",
+		h: func(e broker.Event) error {
+			if string(e.Message().Body) == "fail" {
+				return errors.New("handler failed")
+			}
+			handled = append(handled, string(e.Message().Body))
+			return nil
+		},
+	}
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 3)}
+	sess := &fakeSession{}
+
+	claim.messages <- &sarama.ConsumerMessage{Value: []byte("not json"), Offset: 1}
+	claim.messages <- &sarama.ConsumerMessage{Value: mustMarshalBody(t, "fail"), Offset: 2}
+	claim.messages <- &sarama.ConsumerMessage{Value: mustMarshalBody(t, "ok"), Offset: 3}
+	close(claim.messages)
+
+	if err := h.ConsumeClaim(sess, claim); err == nil {
+		t.Fatal("expected ConsumeClaim to return the handler error")
+	}
+	if len(handled) != 0 {
+		t.Fatalf("got handled=%v, want consumption to stop at the failed message", handled)
+	}
+	if len(sess.marked) != 1 || sess.marked[0] != 1 {
+		t.Fatalf("got marked offsets=%v, want only the malformed (1) offset marked, leaving the failed one (2) and everything after it for redelivery", sess.marked)
+	}
+}
+
+func mustMarshalBody(t *testing.T, body string) []byte {
+	t.Helper()
+	b, err := json.Marshal(&broker.Message{Body: []byte(body)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,88 @@
+// Package memory implements broker.Broker in-process, with no external
+// dependency, and is the broker.FromEnv default. It's mainly useful for
+// tests and single-process deployments.
+package memory
+
+import (
+	"sync"
+
+	"github.com/pthethanh/micro/broker"
+)
+
+func init() {
+	broker.Register("memory", func() (broker.Broker, error) {
+		return New(), nil
+	})
+}
+
+// Broker is a broker.Broker that delivers messages to subscribers in the
+// same process, synchronously, in the goroutine that calls Publish.
+type Broker struct {
+	mu   sync.RWMutex
+	subs map[string][]*subscription
+}
+
+// New returns a ready to use in-process Broker.
+func New() *Broker {
+	return &Broker{
+		subs: map[string][]*subscription{},
+	}
+}
+
+// Publish implements broker.Broker.
+func (b *Broker) Publish(topic string, m *broker.Message) error {
+	b.mu.RLock()
+	subs := append([]*subscription{}, b.subs[topic]...)
+	b.mu.RUnlock()
+	e := &event{topic: topic, msg: m}
+	for _, sub := range subs {
+		if err := sub.h(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe implements broker.Broker.
+func (b *Broker) Subscribe(topic string, h func(broker.Event) error) (broker.Subscription, error) {
+	sub := &subscription{b: b, topic: topic, h: h}
+	b.mu.Lock()
+	b.subs[topic] = append(b.subs[topic], sub)
+	b.mu.Unlock()
+	return sub, nil
+}
+
+// Close implements broker.Broker.
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	b.subs = map[string][]*subscription{}
+	b.mu.Unlock()
+	return nil
+}
+
+type event struct {
+	topic string
+	msg   *broker.Message
+}
+
+func (e *event) Topic() string            { return e.topic }
+func (e *event) Message() *broker.Message { return e.msg }
+
+type subscription struct {
+	b     *Broker
+	topic string
+	h     func(broker.Event) error
+}
+
+func (s *subscription) Unsubscribe() error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	subs := s.b.subs[s.topic]
+	for i, sub := range subs {
+		if sub == s {
+			s.b.subs[s.topic] = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
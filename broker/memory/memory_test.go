@@ -45,6 +45,34 @@ func TestBroker(t *testing.T) {
 	}
 }
 
+func TestBrokerCloudEventFields(t *testing.T) {
+	b := memory.New()
+	ch := make(chan broker.Event, 1)
+	sub, err := b.Subscribe("events", func(msg broker.Event) error {
+		ch <- msg
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	m := &broker.Message{
+		ID:          "1",
+		Source:      "/test",
+		Type:        "test.event",
+		SpecVersion: "1.0",
+		Body:        []byte(`{"ok":true}`),
+	}
+	if err := b.Publish("events", m); err != nil {
+		t.Fatal(err)
+	}
+	e := <-ch
+	if e.Message().ID != "1" || e.Message().Source != "/test" || e.Message().Type != "test.event" {
+		t.Fatalf("got message=%+v, want CloudEvents fields to pass through unchanged", e.Message())
+	}
+}
+
 func mustNewMessage(enc func(v interface{}) ([]byte, error), body interface{}, header map[string]string) *broker.Message {
 	b, err := enc(body)
 	if err != nil {
@@ -0,0 +1,142 @@
+// Package nats implements broker.Broker on top of NATS JetStream, giving
+// durable consumer groups, at-least-once delivery and automatic reconnect
+// with backoff so the micro framework can be used for real event-driven
+// services instead of in-process tests only.
+package nats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/pthethanh/micro/broker"
+)
+
+func init() {
+	broker.Register("nats", func() (broker.Broker, error) {
+		url := os.Getenv("NATS_URL")
+		if url == "" {
+			url = nats.DefaultURL
+		}
+		return New(url, Group(os.Getenv("NATS_QUEUE_GROUP")))
+	})
+}
+
+const defaultAckWait = 30 * time.Second
+
+// Broker is a broker.Broker backed by a NATS JetStream connection.
+type Broker struct {
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+	group   string
+	ackWait time.Duration
+}
+
+// Option customizes a Broker returned by New.
+type Option func(*Broker)
+
+// Group sets the durable consumer group name joined by every Subscribe call
+// on the Broker. Subscribers sharing a group load-balance a topic's
+// messages between them instead of each receiving every message.
+func Group(name string) Option {
+	return func(b *Broker) {
+		if name != "" {
+			b.group = name
+		}
+	}
+}
+
+// AckWait overrides the default ack timeout a JetStream consumer waits for
+// before redelivering an unacknowledged message.
+func AckWait(d time.Duration) Option {
+	return func(b *Broker) {
+		b.ackWait = d
+	}
+}
+
+// New connects to the NATS server at url and returns a Broker backed by
+// JetStream. The underlying connection reconnects indefinitely with the
+// client library's own backoff should it drop.
+func New(url string, opts ...Option) (*Broker, error) {
+	b := &Broker{
+		group:   "micro",
+		ackWait: defaultAckWait,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	conn, err := nats.Connect(url,
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(2*time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("nats: connect: %w", err)
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("nats: jetstream: %w", err)
+	}
+	b.conn = conn
+	b.js = js
+	return b, nil
+}
+
+// Publish implements broker.Broker.
+func (b *Broker) Publish(topic string, m *broker.Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("nats: encode message: %w", err)
+	}
+	if _, err := b.js.Publish(topic, data); err != nil {
+		return fmt.Errorf("nats: publish: %w", err)
+	}
+	return nil
+}
+
+// Subscribe implements broker.Broker. It joins the Broker's durable
+// consumer group for topic, so at-least-once delivery is preserved across
+// reconnects and multiple subscribers on the same group share the load.
+func (b *Broker) Subscribe(topic string, h func(broker.Event) error) (broker.Subscription, error) {
+	sub, err := b.js.QueueSubscribe(topic, b.group, func(msg *nats.Msg) {
+		m := &broker.Message{}
+		if err := json.Unmarshal(msg.Data, m); err != nil {
+			msg.Ack() // malformed payload, do not keep redelivering it
+			return
+		}
+		if err := h(&event{topic: topic, msg: m}); err != nil {
+			msg.Nak()
+			return
+		}
+		msg.Ack()
+	}, nats.Durable(b.group), nats.ManualAck(), nats.AckWait(b.ackWait))
+	if err != nil {
+		return nil, fmt.Errorf("nats: subscribe: %w", err)
+	}
+	return &subscription{sub: sub}, nil
+}
+
+// Close closes the underlying NATS connection. It does not drain it first.
+func (b *Broker) Close() error {
+	b.conn.Close()
+	return nil
+}
+
+type event struct {
+	topic string
+	msg   *broker.Message
+}
+
+func (e *event) Topic() string            { return e.topic }
+func (e *event) Message() *broker.Message { return e.msg }
+
+type subscription struct {
+	sub *nats.Subscription
+}
+
+func (s *subscription) Unsubscribe() error {
+	return s.sub.Unsubscribe()
+}
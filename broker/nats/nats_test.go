@@ -0,0 +1,26 @@
+package nats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupEmptyNamePreservesDefault(t *testing.T) {
+	b := &Broker{group: "micro"}
+	Group("")(b)
+	if b.group != "micro" {
+		t.Fatalf("got group=%q, want the default %q to be preserved", b.group, "micro")
+	}
+	Group("custom")(b)
+	if b.group != "custom" {
+		t.Fatalf("got group=%q, want group=%q", b.group, "custom")
+	}
+}
+
+func TestAckWaitOverridesDefault(t *testing.T) {
+	b := &Broker{ackWait: defaultAckWait}
+	AckWait(5 * time.Second)(b)
+	if b.ackWait != 5*time.Second {
+		t.Fatalf("got ackWait=%s, want %s", b.ackWait, 5*time.Second)
+	}
+}
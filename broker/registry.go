@@ -0,0 +1,48 @@
+package broker
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Factory constructs a Broker from its own environment variables (e.g. a
+// connection URL). Implementations register themselves under a name via
+// Register, typically from an init() function, so FromEnv can pick one at
+// runtime without this package having to import every implementation.
+type Factory func() (Broker, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{}
+)
+
+// Register makes a Broker implementation available under name for use by
+// FromEnv. It is meant to be called from an implementation package's init(),
+// after the caller has blank-imported that package, e.g.
+// `_ "github.com/pthethanh/micro/broker/nats"`. This mirrors the pattern
+// used by database/sql drivers.
+func Register(name string, f Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	factories[name] = f
+}
+
+// FromEnv constructs a Broker based on the BROKER environment variable
+// (memory|nats|kafka, defaulting to "memory"), using the Factory previously
+// registered under that name. Callers must blank-import the implementation
+// package they want, so it can register itself and read its own connection
+// settings from the environment (e.g. NATS_URL, KAFKA_BROKERS).
+func FromEnv() (Broker, error) {
+	name := os.Getenv("BROKER")
+	if name == "" {
+		name = "memory"
+	}
+	mu.RLock()
+	f, ok := factories[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("broker: no broker registered under BROKER=%q, did you forget to blank-import its package?", name)
+	}
+	return f()
+}
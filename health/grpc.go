@@ -0,0 +1,167 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// GRPCServer implements grpc_health_v1.HealthServer. The status of the ""
+// (overall) service is derived from a set of CheckFuncs, re-evaluated every
+// `interval`; other services can be driven directly through
+// SetServingStatus so long-running init such as a DB migration or a broker
+// connect can gate traffic until it completes.
+type GRPCServer struct {
+	checks   []CheckFunc
+	interval time.Duration
+
+	mu       sync.RWMutex
+	statuses map[string]grpc_health_v1.HealthCheckResponse_ServingStatus
+	watchers map[string][]chan grpc_health_v1.HealthCheckResponse_ServingStatus
+
+	readyOnce sync.Once
+	readyCh   chan struct{}
+
+	stop chan struct{}
+}
+
+// NewGRPCServer returns a GRPCServer that re-evaluates checks every
+// interval to derive the status of the "" (overall) service. A non-positive
+// interval evaluates checks once, at construction time, only.
+func NewGRPCServer(checks []CheckFunc, interval time.Duration) *GRPCServer {
+	s := &GRPCServer{
+		checks:   checks,
+		interval: interval,
+		statuses: map[string]grpc_health_v1.HealthCheckResponse_ServingStatus{
+			"": grpc_health_v1.HealthCheckResponse_NOT_SERVING,
+		},
+		watchers: map[string][]chan grpc_health_v1.HealthCheckResponse_ServingStatus{},
+		readyCh:  make(chan struct{}),
+		stop:     make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *GRPCServer) run() {
+	s.evaluate()
+	if s.interval <= 0 {
+		return
+	}
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			s.evaluate()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Stop stops the periodic re-evaluation ticker started by NewGRPCServer, so
+// it doesn't outlive the server. Active Watch streams are left to unwind on
+// their own, through stream.Context().Done(), once the gRPC server that
+// owns them shuts down. Its signature matches io.Closer's Close, so callers
+// such as server.HealthGRPC can adapt it to io.Closer and hand off its
+// lifecycle the same way JWTAuthWithJWKS does for its refresh loop.
+func (s *GRPCServer) Stop() error {
+	close(s.stop)
+	return nil
+}
+
+func (s *GRPCServer) evaluate() {
+	ctx := context.Background()
+	status := grpc_health_v1.HealthCheckResponse_SERVING
+	for _, check := range s.checks {
+		if err := check(ctx); err != nil {
+			status = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+			break
+		}
+	}
+	s.SetServingStatus("", status)
+	if status == grpc_health_v1.HealthCheckResponse_SERVING {
+		s.readyOnce.Do(func() { close(s.readyCh) })
+	}
+}
+
+// Ready reports whether every registered check has succeeded at least once.
+func (s *GRPCServer) Ready() bool {
+	select {
+	case <-s.readyCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// SetServingStatus sets the status reported for service and notifies any
+// active Watch streams for it. Use "" for the overall server status.
+func (s *GRPCServer) SetServingStatus(service string, status grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	s.mu.Lock()
+	s.statuses[service] = status
+	watchers := append([]chan grpc_health_v1.HealthCheckResponse_ServingStatus{}, s.watchers[service]...)
+	s.mu.Unlock()
+	for _, ch := range watchers {
+		select {
+		case ch <- status:
+		default:
+		}
+	}
+}
+
+// Check implements grpc_health_v1.HealthServer.
+func (s *GRPCServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	status, ok := s.statuses[req.Service]
+	if !ok {
+		status = grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: status}, nil
+}
+
+// Watch implements grpc_health_v1.HealthServer, streaming status updates
+// for req.Service as they happen.
+func (s *GRPCServer) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	ch := make(chan grpc_health_v1.HealthCheckResponse_ServingStatus, 1)
+	s.mu.Lock()
+	s.watchers[req.Service] = append(s.watchers[req.Service], ch)
+	current, ok := s.statuses[req.Service]
+	if !ok {
+		current = grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	s.mu.Unlock()
+	defer s.removeWatcher(req.Service, ch)
+	if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: current}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case status := <-ch:
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: status}); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// removeWatcher drops ch from the watcher list for service, so a stream
+// that has ended (client disconnect, context cancellation or a Send error)
+// no longer receives updates or holds its slot open forever.
+func (s *GRPCServer) removeWatcher(service string, ch chan grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	watchers := s.watchers[service]
+	for i, c := range watchers {
+		if c == ch {
+			s.watchers[service] = append(watchers[:i:i], watchers[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// fakeWatchStream is a minimal grpc_health_v1.Health_WatchServer backed by
+// a cancelable context, enough to drive GRPCServer.Watch without a real
+// gRPC connection.
+type fakeWatchStream struct {
+	ctx  context.Context
+	sent chan *grpc_health_v1.HealthCheckResponse
+}
+
+func (s *fakeWatchStream) Send(resp *grpc_health_v1.HealthCheckResponse) error {
+	s.sent <- resp
+	return nil
+}
+func (s *fakeWatchStream) SetHeader(metadata.MD) error  { return nil }
+func (s *fakeWatchStream) SendHeader(metadata.MD) error { return nil }
+func (s *fakeWatchStream) SetTrailer(metadata.MD)       {}
+func (s *fakeWatchStream) Context() context.Context     { return s.ctx }
+func (s *fakeWatchStream) SendMsg(m interface{}) error  { return nil }
+func (s *fakeWatchStream) RecvMsg(m interface{}) error  { return nil }
+
+func TestWatchRemovesWatcherWhenStreamEnds(t *testing.T) {
+	s := NewGRPCServer(nil, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &fakeWatchStream{ctx: ctx, sent: make(chan *grpc_health_v1.HealthCheckResponse, 1)}
+
+	done := make(chan error, 1)
+	go func() { done <- s.Watch(&grpc_health_v1.HealthCheckRequest{Service: "svc"}, stream) }()
+
+	<-stream.sent // initial status sent once the watcher is registered
+
+	s.mu.RLock()
+	if len(s.watchers["svc"]) != 1 {
+		s.mu.RUnlock()
+		t.Fatalf("got %d watchers, want 1 while the stream is open", len(s.watchers["svc"]))
+	}
+	s.mu.RUnlock()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Watch did not return after its context was canceled")
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.watchers["svc"]) != 0 {
+		t.Fatalf("got %d watchers, want 0 after the stream ended", len(s.watchers["svc"]))
+	}
+}
+
+func TestStopStopsReEvaluation(t *testing.T) {
+	var calls int32
+	check := func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}
+	s := NewGRPCServer([]CheckFunc{check}, 10*time.Millisecond)
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	// Give the ticker goroutine a moment to observe s.stop and exit, then
+	// confirm no further evaluation happens.
+	time.Sleep(20 * time.Millisecond)
+	before := atomic.LoadInt32(&calls)
+	time.Sleep(50 * time.Millisecond)
+	if after := atomic.LoadInt32(&calls); after != before {
+		t.Fatalf("got %d evaluations after Stop, want none", after-before)
+	}
+}
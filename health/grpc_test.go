@@ -0,0 +1,49 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pthethanh/micro/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestGRPCServerCheck(t *testing.T) {
+	failing := true
+	check := func(ctx context.Context) error {
+		if failing {
+			return errors.New("not ready")
+		}
+		return nil
+	}
+	s := health.NewGRPCServer([]health.CheckFunc{check}, 0)
+
+	resp, err := s.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("got status=%v, want NOT_SERVING", resp.Status)
+	}
+	if s.Ready() {
+		t.Fatal("expected Ready() to be false before any check has succeeded")
+	}
+
+	s.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+	resp, err = s.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Fatalf("got status=%v, want SERVING", resp.Status)
+	}
+
+	unknown, err := s.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unknown.Status != grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN {
+		t.Fatalf("got status=%v, want SERVICE_UNKNOWN", unknown.Status)
+	}
+}
@@ -0,0 +1,11 @@
+// Package health provides the building blocks the server package uses to
+// back its HTTP /ready and /live endpoints, and a grpc.health.v1.Health
+// service implementation driven by the same checks.
+package health
+
+import "context"
+
+// CheckFunc reports whether a dependency is currently healthy. It should be
+// fast and side-effect free: a database ping, a broker connection check,
+// and so on.
+type CheckFunc func(ctx context.Context) error
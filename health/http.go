@@ -0,0 +1,47 @@
+package health
+
+import (
+	"net/http"
+	"sync"
+)
+
+// ReadinessHandler returns an http.Handler that runs checks on every
+// request and responds 503 until every one of them has succeeded at least
+// once, then 503 again any time the most recent run failed. This is what
+// backs the server package's readiness probe: it must not report ready
+// before dependencies such as a DB migration or a broker connect have
+// completed, or a Kubernetes rolling update will route traffic too early.
+func ReadinessHandler(checks ...CheckFunc) http.Handler {
+	h := &readinessHandler{checks: checks}
+	return h
+}
+
+type readinessHandler struct {
+	checks []CheckFunc
+
+	mu          sync.Mutex
+	everServing bool
+}
+
+func (h *readinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ok := true
+	for _, check := range h.checks {
+		if err := check(ctx); err != nil {
+			ok = false
+			break
+		}
+	}
+	h.mu.Lock()
+	if ok {
+		h.everServing = true
+	}
+	serving := h.everServing && ok
+	h.mu.Unlock()
+
+	if !serving {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,41 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pthethanh/micro/health"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	failing := true
+	check := func(ctx context.Context) error {
+		if failing {
+			return errors.New("not ready")
+		}
+		return nil
+	}
+	h := health.ReadinessHandler(check)
+	get := func() int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return w.Code
+	}
+
+	if got := get(); got != http.StatusServiceUnavailable {
+		t.Fatalf("got status=%d, want=%d before the first successful check", got, http.StatusServiceUnavailable)
+	}
+
+	failing = false
+	if got := get(); got != http.StatusOK {
+		t.Fatalf("got status=%d, want=%d once the check succeeds", got, http.StatusOK)
+	}
+
+	failing = true
+	if got := get(); got != http.StatusServiceUnavailable {
+		t.Fatalf("got status=%d, want=%d once a later check fails again", got, http.StatusServiceUnavailable)
+	}
+}
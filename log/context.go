@@ -0,0 +1,20 @@
+package log
+
+import "context"
+
+// loggerKey is the context key used to attach a request-scoped Logger.
+type loggerKey struct{}
+
+// NewContext returns a new Context that carries l.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the Logger previously attached to ctx by NewContext,
+// falling back to Root if none was attached.
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerKey{}).(Logger); ok {
+		return l
+	}
+	return root
+}
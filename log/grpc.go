@@ -0,0 +1,104 @@
+package log
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/pthethanh/micro/auth/jwt"
+)
+
+// requestScopedLogger builds a child of base seeded with request-id,
+// method, peer and, when available, the caller's user/client id and W3C
+// trace correlation so every line a handler emits through log.FromContext
+// can be joined back to a single RPC in a system like Loki/Tempo/Jaeger.
+func requestScopedLogger(ctx context.Context, base Logger, method string) Logger {
+	fields := []interface{}{"request-id", requestIDOf(ctx), "method", method}
+	if p, ok := peer.FromContext(ctx); ok {
+		fields = append(fields, "peer", p.Addr.String())
+	}
+	if claims, ok := jwt.FromContext(ctx); ok {
+		if claims.UserID != "" {
+			fields = append(fields, "user_id", claims.UserID)
+		}
+		if claims.ClientID != "" {
+			fields = append(fields, "client_id", claims.ClientID)
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get("traceparent"); len(vals) > 0 {
+			if tc, ok := parseTraceparent(vals[0]); ok {
+				fields = append(fields, "trace_id", tc.traceID, "span_id", tc.spanID)
+			}
+		}
+	}
+	return base.With(fields...)
+}
+
+// requestIDOf returns the request-id metadata forwarded by the gateway's
+// DefaultHeaderMatcher, generating a new one if the caller sent none.
+func requestIDOf(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get("request-id"); len(vals) > 0 && vals[0] != "" {
+			return vals[0]
+		}
+	}
+	return NewRequestID()
+}
+
+// UnaryInterceptor returns a grpc.UnaryServerInterceptor that attaches a
+// request-scoped child of logger to the context (retrievable through
+// FromContext) and emits a single access-log line at the end of the call
+// with latency, status code and error.
+func UnaryInterceptor(logger Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		l := requestScopedLogger(ctx, logger, info.FullMethod)
+		resp, err := handler(NewContext(ctx, l), req)
+		l.With(
+			"latency", time.Since(start).String(),
+			"code", status.Code(err).String(),
+			"error", errString(err),
+		).Info("rpc completed")
+		return resp, err
+	}
+}
+
+// StreamInterceptor returns a grpc.StreamServerInterceptor that attaches a
+// request-scoped child of logger to the stream's context (retrievable
+// through FromContext) and emits a single access-log line at the end of
+// the call with latency, status code and error.
+func StreamInterceptor(logger Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		l := requestScopedLogger(ss.Context(), logger, info.FullMethod)
+		err := handler(srv, &contextServerStream{ServerStream: ss, ctx: NewContext(ss.Context(), l)})
+		l.With(
+			"latency", time.Since(start).String(),
+			"code", status.Code(err).String(),
+			"error", errString(err),
+		).Info("rpc completed")
+		return err
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// contextServerStream wraps a grpc.ServerStream to override its Context.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
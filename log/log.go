@@ -0,0 +1,98 @@
+// Package log provides the structured logger used throughout the
+// framework, plus the context and gRPC interceptor plumbing needed to carry
+// a request-scoped Logger across a call.
+package log
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Logger is a structured logger. With returns a child Logger that includes
+// the given key/value pairs on every subsequent line, so a request-scoped
+// logger can be built by seeding a base Logger with fields such as
+// request-id, method or user_id.
+type Logger interface {
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+	Panic(args ...interface{})
+	With(keyvals ...interface{}) Logger
+}
+
+type stdLogger struct {
+	std    *log.Logger
+	fields []interface{}
+}
+
+// New returns a Logger that writes to os.Stderr, optionally seeded with
+// keyvals (e.g. New("service", "order-svc")).
+func New(keyvals ...interface{}) Logger {
+	return &stdLogger{
+		std:    log.New(os.Stderr, "", log.LstdFlags|log.Lmicroseconds),
+		fields: keyvals,
+	}
+}
+
+func (l *stdLogger) print(level string, args ...interface{}) {
+	l.std.Printf("level=%s msg=%q%s", level, fmt.Sprint(args...), formatFields(l.fields))
+}
+
+func (l *stdLogger) Debug(args ...interface{}) { l.print("debug", args...) }
+func (l *stdLogger) Info(args ...interface{})  { l.print("info", args...) }
+func (l *stdLogger) Warn(args ...interface{})  { l.print("warn", args...) }
+func (l *stdLogger) Error(args ...interface{}) { l.print("error", args...) }
+func (l *stdLogger) Panic(args ...interface{}) {
+	l.print("panic", args...)
+	panic(fmt.Sprint(args...))
+}
+
+func (l *stdLogger) With(keyvals ...interface{}) Logger {
+	fields := make([]interface{}, 0, len(l.fields)+len(keyvals))
+	fields = append(fields, l.fields...)
+	fields = append(fields, keyvals...)
+	return &stdLogger{std: l.std, fields: fields}
+}
+
+func formatFields(fields []interface{}) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for i := 0; i+1 < len(fields); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", fields[i], fields[i+1])
+	}
+	return b.String()
+}
+
+// root is the package-level default Logger backing Root and the
+// package-level Debug/Info/Warn/Error/Panic functions.
+var root = New()
+
+// Root returns the package's default Logger.
+func Root() Logger {
+	return root
+}
+
+// SetRoot replaces the package's default Logger.
+func SetRoot(l Logger) {
+	root = l
+}
+
+// Debug logs to the root Logger.
+func Debug(args ...interface{}) { root.Debug(args...) }
+
+// Info logs to the root Logger.
+func Info(args ...interface{}) { root.Info(args...) }
+
+// Warn logs to the root Logger.
+func Warn(args ...interface{}) { root.Warn(args...) }
+
+// Error logs to the root Logger.
+func Error(args ...interface{}) { root.Error(args...) }
+
+// Panic logs to the root Logger, then panics.
+func Panic(args ...interface{}) { root.Panic(args...) }
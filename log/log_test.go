@@ -0,0 +1,24 @@
+package log
+
+import "testing"
+
+func TestParseTraceparent(t *testing.T) {
+	tc, ok := parseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if !ok {
+		t.Fatal("expected a valid traceparent to parse")
+	}
+	if tc.traceID != "4bf92f3577b34da6a3ce929d0e0e4736" || tc.spanID != "00f067aa0ba902b7" {
+		t.Fatalf("unexpected trace context: %+v", tc)
+	}
+	if _, ok := parseTraceparent("not-a-traceparent"); ok {
+		t.Fatal("expected an invalid traceparent to be rejected")
+	}
+}
+
+func TestRequestIDGeneratorOverride(t *testing.T) {
+	defer SetRequestIDGenerator(defaultRequestIDGenerator)
+	SetRequestIDGenerator(func() string { return "fixed-id" })
+	if got := NewRequestID(); got != "fixed-id" {
+		t.Fatalf("got request id=%s, want fixed-id", got)
+	}
+}
@@ -0,0 +1,33 @@
+package log
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// requestIDGenerator produces a new request id when none was forwarded by
+// the caller. It defaults to a random 16 byte hex string; plug in a
+// ULID/UUIDv7 generator with SetRequestIDGenerator if you need a sortable
+// or externally-recognizable format.
+var requestIDGenerator = defaultRequestIDGenerator
+
+// SetRequestIDGenerator overrides the function used to generate a request
+// id when the incoming call carries none.
+func SetRequestIDGenerator(f func() string) {
+	if f != nil {
+		requestIDGenerator = f
+	}
+}
+
+// NewRequestID generates a new request id using the configured generator.
+func NewRequestID() string {
+	return requestIDGenerator()
+}
+
+func defaultRequestIDGenerator() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
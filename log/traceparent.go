@@ -0,0 +1,19 @@
+package log
+
+import "strings"
+
+// traceContext is the subset of a W3C traceparent header
+// (https://www.w3.org/TR/trace-context/) this package correlates logs
+// with: "{version}-{trace_id}-{span_id}-{flags}".
+type traceContext struct {
+	traceID string
+	spanID  string
+}
+
+func parseTraceparent(h string) (traceContext, bool) {
+	parts := strings.Split(h, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return traceContext{}, false
+	}
+	return traceContext{traceID: parts[1], spanID: parts[2]}, true
+}
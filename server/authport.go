@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pthethanh/micro/broker"
+	"github.com/pthethanh/micro/log"
+)
+
+// AuthPortOption customizes the admin HTTP server started by AuthPort.
+type AuthPortOption func(*http.ServeMux)
+
+// WithConfigDump mounts a GET /debug/config endpoint serving the JSON
+// encoding of whatever dump returns, typically a service's own non-secret
+// configuration.
+func WithConfigDump(dump func() interface{}) AuthPortOption {
+	return func(mux *http.ServeMux) {
+		mux.HandleFunc("/debug/config", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, dump())
+		})
+	}
+}
+
+// WithBrokerDebug mounts a GET /debug/broker endpoint exposing b for
+// inspection.
+func WithBrokerDebug(b broker.Broker) AuthPortOption {
+	return func(mux *http.ServeMux) {
+		mux.HandleFunc("/debug/broker", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, fmt.Sprintf("%T", b))
+		})
+	}
+}
+
+// WithLogLevel mounts a POST /debug/log-level?level=debug endpoint that
+// calls setLevel, so the log level can be changed at runtime.
+func WithLogLevel(setLevel func(level string) error) AuthPortOption {
+	return func(mux *http.ServeMux) {
+		mux.HandleFunc("/debug/log-level", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			level := r.URL.Query().Get("level")
+			if err := setLevel(level); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// AuthPort starts a second HTTP listener, bound only to addr, exposing
+// admin/debug surfaces (pprof, expvar, forced GC, and whatever is added
+// through adminOpts) behind an HS256 JWT bearer token. The shared secret is
+// loaded from secretFile and re-read on SIGHUP so it can be rotated without
+// a restart. Tokens whose `iat` claim is more than 60s old are rejected, to
+// keep a captured admin token from being replayed long after issuance.
+//
+// Borrowed from go-ethereum's split of unauthenticated public RPC and
+// JWT-authenticated engine API: pprof on the public port is not safe to
+// expose, so admin surfaces get their own authenticated listener instead of
+// being gated by the app's own JWT auth.
+func AuthPort(addr string, secretFile string, adminOpts ...AuthPortOption) Option {
+	return func(opts *Server) {
+		secret, err := newRotatingSecret(secretFile)
+		if err != nil {
+			log.Panic("server: auth port, err: ", err)
+		}
+		opts.addCloser(secret)
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		mux.Handle("/debug/vars", expvar.Handler())
+		mux.HandleFunc("/debug/gc", func(w http.ResponseWriter, r *http.Request) {
+			runtime.GC()
+			w.WriteHeader(http.StatusNoContent)
+		})
+		for _, opt := range adminOpts {
+			opt(mux)
+		}
+
+		srv := &http.Server{
+			Addr:    addr,
+			Handler: authMiddleware(secret.current, mux),
+		}
+		opts.authPortServer = srv
+	}
+}
+
+// rotatingSecret holds an HMAC secret loaded from a file, reloaded on
+// SIGHUP so operators can rotate it without restarting the process.
+type rotatingSecret struct {
+	path string
+
+	mu     sync.RWMutex
+	secret []byte
+
+	sigCh chan os.Signal
+}
+
+func newRotatingSecret(path string) (*rotatingSecret, error) {
+	s := &rotatingSecret{path: path}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	s.sigCh = make(chan os.Signal, 1)
+	signal.Notify(s.sigCh, syscall.SIGHUP)
+	go func() {
+		for range s.sigCh {
+			if err := s.reload(); err != nil {
+				log.Error("server: reload auth port secret, err: ", err)
+			}
+		}
+	}()
+	return s, nil
+}
+
+// Close stops listening for SIGHUP and ends the reload goroutine started by
+// newRotatingSecret, so neither outlives the Server that owns it. It
+// implements io.Closer so AuthPort can hand off its lifecycle the same way
+// JWTAuthWithJWKS does for its JWKS refresh loop.
+func (s *rotatingSecret) Close() error {
+	signal.Stop(s.sigCh)
+	close(s.sigCh)
+	return nil
+}
+
+func (s *rotatingSecret) reload() error {
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("server: read auth port secret: %w", err)
+	}
+	s.mu.Lock()
+	s.secret = []byte(strings.TrimSpace(string(data)))
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *rotatingSecret) current() []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.secret
+}
+
+const maxTokenAge = 60 * time.Second
+
+// authMiddleware requires a valid HS256 bearer token, signed with the key
+// returned by secret, whose `iat` is no older than maxTokenAge.
+func authMiddleware(secret func() []byte, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenStr := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if tokenStr == "" {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+		claims := jwt.StandardClaims{}
+		_, err := jwt.ParseWithClaims(tokenStr, &claims, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+			}
+			return secret(), nil
+		})
+		if err != nil {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		if claims.IssuedAt == 0 || time.Since(time.Unix(claims.IssuedAt, 0)) > maxTokenAge {
+			http.Error(w, "token too old", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ListenAndServeAuthPort starts the admin HTTP listener configured through
+// AuthPort, if any, blocking until ctx is canceled. Server.ListenAndServeContext
+// calls this automatically, so callers configuring AuthPort never need to
+// invoke it themselves.
+func (s *Server) ListenAndServeAuthPort(ctx context.Context) error {
+	if s.authPortServer == nil {
+		return nil
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.authPortServer.ListenAndServe()
+	}()
+	select {
+	case <-ctx.Done():
+		return s.authPortServer.Shutdown(context.Background())
+	case err := <-errCh:
+		return err
+	}
+}
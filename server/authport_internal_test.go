@@ -0,0 +1,86 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func signToken(t *testing.T, iat time.Time, secret []byte) string {
+	t.Helper()
+	claims := jwt.StandardClaims{IssuedAt: iat.Unix()}
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	secret := []byte("top-secret")
+	h := authMiddleware(func() []byte { return secret }, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name       string
+		authHeader string
+		wantStatus int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"valid token", "Bearer " + signToken(t, time.Now(), secret), http.StatusNoContent},
+		{"expired token", "Bearer " + signToken(t, time.Now().Add(-2*time.Minute), secret), http.StatusUnauthorized},
+		{"wrong secret", "Bearer " + signToken(t, time.Now(), []byte("wrong-secret")), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("got status=%d, want=%d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRotatingSecretReload(t *testing.T) {
+	f, err := ioutil.TempFile("", "authport-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("first-secret"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	s, err := newRotatingSecret(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if got := string(s.current()); got != "first-secret" {
+		t.Fatalf("got secret=%s, want=first-secret", got)
+	}
+
+	// Simulate the SIGHUP-triggered reload directly, rather than sending a
+	// real signal, to keep the test deterministic.
+	if err := ioutil.WriteFile(f.Name(), []byte("second-secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(s.current()); got != "second-secret" {
+		t.Fatalf("got secret=%s, want=second-secret", got)
+	}
+}
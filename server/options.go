@@ -2,6 +2,7 @@ package server
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/textproto"
 	"os"
@@ -10,10 +11,13 @@ import (
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/pthethanh/micro/auth"
 	"github.com/pthethanh/micro/auth/jwt"
+	"github.com/pthethanh/micro/broker"
+	"github.com/pthethanh/micro/broker/cloudevents"
 	"github.com/pthethanh/micro/health"
 	"github.com/pthethanh/micro/log"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const (
@@ -34,13 +38,36 @@ func UnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
 	}
 }
 
-// JWTAuth is an option allow to add jwt authenticator to the server.
+// JWTAuth is an option allow to add jwt authenticator to the server,
+// verifying tokens against a static shared secret.
 func JWTAuth(secret string) Option {
 	return func(opts *Server) {
 		if secret == "" {
 			return
 		}
-		f := jwt.Authenticator([]byte(secret))
+		f := jwt.Authenticator(jwt.NewStaticSecretSource([]byte(secret)))
+		opts.streamInterceptors = append(opts.streamInterceptors, auth.StreamInterceptor(f))
+		opts.unaryInterceptors = append(opts.unaryInterceptors, auth.UnaryInterceptor(f))
+	}
+}
+
+// JWTAuthWithJWKS is an option allow to add a jwt authenticator backed by an
+// OIDC issuer's JWKS endpoint instead of a static secret. It performs OIDC
+// discovery against issuer to locate the `jwks_uri`, refreshes the key set
+// every `refresh` interval so keys can rotate without a server restart, and
+// validates the `iss`/`aud`/`exp`/`nbf` claims of incoming tokens. This is
+// what IdPs such as Keycloak, Auth0 or Ory expect of a relying party. The
+// source's background refresh loop is stopped when the Server shuts down.
+func JWTAuthWithJWKS(issuer, audience string, refresh time.Duration) Option {
+	return func(opts *Server) {
+		src, err := jwt.NewJWKSSourceFromIssuer(issuer, refresh)
+		if err != nil {
+			log.Panic("server: jwt auth with jwks, err: ", err)
+		}
+		if c, ok := src.(io.Closer); ok {
+			opts.addCloser(c)
+		}
+		f := jwt.Authenticator(src, jwt.WithIssuer(issuer), jwt.WithAudience(audience))
 		opts.streamInterceptors = append(opts.streamInterceptors, auth.StreamInterceptor(f))
 		opts.unaryInterceptors = append(opts.unaryInterceptors, auth.UnaryInterceptor(f))
 	}
@@ -54,6 +81,41 @@ func WithAuth(f auth.AuthenticatorFunc) Option {
 	}
 }
 
+// Authorize is an option allow to add a per-RPC authorization layer to the
+// server, and lets services move fine-grained access control out of handler
+// code. Interceptors run in the order their Options are applied, so az runs
+// after, and can rely on whatever was attached to the context (e.g.
+// jwt.Claims) by, an AuthenticatorFunc whose Option precedes Authorize; one
+// given after Authorize runs after az.
+func Authorize(az auth.Authorizer) Option {
+	return func(opts *Server) {
+		opts.streamInterceptors = append(opts.streamInterceptors, auth.AuthorizeStreamInterceptor(az))
+		opts.unaryInterceptors = append(opts.unaryInterceptors, auth.AuthorizeUnaryInterceptor(az))
+	}
+}
+
+// Broker is an option allow to expose b to handlers. Every unary and
+// streaming request context carries b, retrievable with broker.FromContext,
+// so services can publish/subscribe without threading it through every
+// constructor. Use broker.FromEnv to pick an implementation at runtime.
+func Broker(b broker.Broker) Option {
+	return func(opts *Server) {
+		opts.streamInterceptors = append(opts.streamInterceptors, broker.StreamInterceptor(b))
+		opts.unaryInterceptors = append(opts.unaryInterceptors, broker.UnaryInterceptor(b))
+	}
+}
+
+// CloudEventsHandler is an option allow to mount an HTTP endpoint on the
+// existing gateway mux that accepts POSTed CloudEvents (binary or
+// structured content mode) and republishes them onto b under every topic
+// in topics, so services can interop with Knative Eventing and other CE
+// producers without hand-rolling headers.
+func CloudEventsHandler(b broker.Broker, path string, topics ...string) Option {
+	return func(opts *Server) {
+		opts.getOrCreateRouter().Path(path).Methods(http.MethodPost).Handler(cloudevents.HTTPHandler(b, topics...))
+	}
+}
+
 // Logger is an option allow add a custom logger into the server.
 func Logger(logger log.Logger) Option {
 	return func(opts *Server) {
@@ -89,6 +151,15 @@ func MetricsPaths(ready, live, metrics string) Option {
 	}
 }
 
+// RequestIDGenerator is an option to override how a request id is
+// generated when an incoming call carries none, e.g. to plug in a
+// ULID/UUIDv7 generator instead of the package's default random id.
+func RequestIDGenerator(f func() string) Option {
+	return func(opts *Server) {
+		log.SetRequestIDGenerator(f)
+	}
+}
+
 // Timeout is an option to override default read/write timeout.
 func Timeout(read, write time.Duration) Option {
 	if read == 0 {
@@ -125,6 +196,36 @@ func HealthChecks(checks ...health.CheckFunc) Option {
 	}
 }
 
+// HealthGRPC is an option allow to back the standard grpc.health.v1.Health
+// service the server registers with the configured HealthChecks,
+// re-evaluated every `interval`; without it they are evaluated once, at
+// startup. Combined with NotifyServing this is what
+// makes Kubernetes rolling updates gate traffic correctly instead of
+// relying solely on the HTTP readiness probe.
+func HealthGRPC(interval time.Duration) Option {
+	return func(opts *Server) {
+		opts.healthGRPCServer = health.NewGRPCServer(opts.healthChecks, interval)
+		opts.addCloser(closerFunc(opts.healthGRPCServer.Stop))
+	}
+}
+
+// NotifyServing updates the serving status reported for service by the
+// grpc.health.v1.Health service registered through HealthGRPC, so
+// long-running initialization (DB migrations, broker connect) can gate
+// traffic without waiting for the next HealthChecks evaluation. It is a
+// no-op if s wasn't built with HealthGRPC.
+//
+// NotifyServing takes s explicitly, rather than reaching for shared package
+// state, so that two Servers built with HealthGRPC in the same process (e.g.
+// a public and an admin listener) each report their own status instead of
+// silently clobbering one another.
+func NotifyServing(s *Server, service string, status grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	if s.healthGRPCServer == nil {
+		return
+	}
+	s.healthGRPCServer.SetServingStatus(service, status)
+}
+
 // AddressFromEnv is an option to get address from environment configuration.
 // It looks for PORT and then ADDRESS variables.
 // This option is mostly used for cloud environment like Heroku where the port
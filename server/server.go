@@ -0,0 +1,189 @@
+// Package server wires a gRPC service and its grpc-gateway HTTP mux behind
+// a single listener, with authentication, authorization, broker, logging
+// and health-check concerns all pluggable through Options.
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/pthethanh/micro/health"
+	"github.com/pthethanh/micro/log"
+)
+
+// Option customizes a Server returned by New.
+type Option func(*Server)
+
+// Server hosts a gRPC server and its grpc-gateway HTTP mux on a single
+// address, applying every cross-cutting concern (auth, logging, health,
+// broker, ...) a caller configures through Option.
+type Server struct {
+	name    string
+	address string
+
+	log log.Logger
+
+	healthChecks     []health.CheckFunc
+	healthGRPCServer *health.GRPCServer
+	authPortServer   *http.Server
+
+	readinessPath string
+	livenessPath  string
+	metricsPath   string
+
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+
+	tlsKeyFile  string
+	tlsCertFile string
+
+	streamInterceptors []grpc.StreamServerInterceptor
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+	serverOptions      []grpc.ServerOption
+	serveMuxOptions    []runtime.ServeMuxOption
+
+	closers []io.Closer
+
+	router *mux.Router
+}
+
+// addCloser registers c to be closed when ListenAndServeContext returns, so
+// an Option that starts background work (e.g. a JWKS refresh loop) has
+// somewhere to give up ownership of its lifecycle instead of leaking it.
+func (s *Server) addCloser(c io.Closer) {
+	s.closers = append(s.closers, c)
+}
+
+// closerFunc adapts an ordinary stop function, such as a Stop() error
+// method that isn't spelled Close, to io.Closer so it can be registered
+// through addCloser.
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+// New returns a Server identified by name, configured by opts.
+func New(name string, opts ...Option) *Server {
+	s := &Server{
+		name:          name,
+		address:       defaultAddr,
+		log:           log.Root(),
+		readinessPath: "/ready",
+		livenessPath:  "/live",
+		metricsPath:   "/metrics",
+		readTimeout:   30 * time.Second,
+		writeTimeout:  30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// getOrCreateRouter returns the Server's HTTP router, creating it on first
+// use, so Options that mount additional HTTP routes (HTTPOnly,
+// CloudEventsHandler) don't each need to know whether one already exists.
+func (s *Server) getOrCreateRouter() *mux.Router {
+	if s.router == nil {
+		s.router = mux.NewRouter()
+	}
+	return s.router
+}
+
+// ListenAndServeContext builds a Server from AddressFromEnv and starts it,
+// blocking until ctx is canceled. It's a convenience for the common case of
+// a single service per process configured entirely through environment
+// variables.
+func ListenAndServeContext(ctx context.Context) error {
+	return New("", AddressFromEnv()).ListenAndServeContext(ctx)
+}
+
+// ListenAndServeContext starts the gRPC server and its HTTP gateway mux on
+// s.address, blocking until ctx is canceled or a fatal error occurs.
+func (s *Server) ListenAndServeContext(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+	defer lis.Close()
+	defer s.closeAll()
+
+	grpcServer := grpc.NewServer(s.grpcServerOptions()...)
+
+	healthSrv := s.healthGRPCServer
+	if healthSrv == nil {
+		healthSrv = health.NewGRPCServer(s.healthChecks, 0)
+	}
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthSrv)
+
+	router := s.getOrCreateRouter()
+	router.HandleFunc(path.Join("/", s.readinessPath), health.ReadinessHandler(s.healthChecks...).ServeHTTP)
+	router.HandleFunc(path.Join("/", s.livenessPath), func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	gatewayMux := runtime.NewServeMux(s.serveMuxOptions...)
+	router.PathPrefix("/").Handler(gatewayMux)
+
+	httpServer := &http.Server{
+		Addr:         s.address,
+		Handler:      grpcOrHTTPHandler(grpcServer, router),
+		ReadTimeout:  s.readTimeout,
+		WriteTimeout: s.writeTimeout,
+	}
+
+	errCh := make(chan error, 2)
+	go func() { errCh <- httpServer.Serve(lis) }()
+	if s.authPortServer != nil {
+		go func() { errCh <- s.ListenAndServeAuthPort(ctx) }()
+	}
+
+	select {
+	case <-ctx.Done():
+		httpServer.Shutdown(context.Background())
+		grpcServer.GracefulStop()
+		return ctx.Err()
+	case err := <-errCh:
+		return err
+	}
+}
+
+// closeAll closes every closer registered through addCloser, ignoring
+// errors since they run during shutdown and have nowhere to report to.
+func (s *Server) closeAll() {
+	for _, c := range s.closers {
+		_ = c.Close()
+	}
+}
+
+func (s *Server) grpcServerOptions() []grpc.ServerOption {
+	opts := append([]grpc.ServerOption{}, s.serverOptions...)
+	opts = append(opts,
+		grpc.ChainStreamInterceptor(s.streamInterceptors...),
+		grpc.ChainUnaryInterceptor(s.unaryInterceptors...),
+	)
+	return opts
+}
+
+// grpcOrHTTPHandler multiplexes gRPC and plain HTTP traffic on a single
+// address, the way co-located grpc-gateway deployments commonly do,
+// dispatching by content-type the same way grpc.Server.ServeHTTP expects.
+func grpcOrHTTPHandler(grpcServer *grpc.Server, httpHandler http.Handler) http.Handler {
+	return h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcServer.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	}), &http2.Server{})
+}
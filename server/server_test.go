@@ -2,11 +2,14 @@ package server_test
 
 import (
 	"context"
+	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/pthethanh/micro/log"
 	"github.com/pthethanh/micro/server"
 	"google.golang.org/grpc"
@@ -63,3 +66,113 @@ func TestInitServerWithOptions(t *testing.T) {
 		}
 	}
 }
+
+// TestListenAndServeContextServesUntilCanceled guards against
+// ListenAndServeContext returning early (with a nil error) before ctx is
+// canceled, e.g. because an unconfigured auxiliary listener short-circuits
+// the select driving it.
+func TestListenAndServeContextServesUntilCanceled(t *testing.T) {
+	addr := ":8002"
+	os.Setenv("ADDRESS", addr)
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Warn("address is already in use, ignore unit test")
+		t.SkipNow()
+		return
+	}
+	l.Close() // close to start the test
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	srv := server.New("", server.AddressFromEnv())
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServeContext(ctx) }()
+
+	// Give the listener a moment to come up, then confirm it's actually
+	// serving rather than having already torn itself down.
+	time.Sleep(50 * time.Millisecond)
+	resp, err := http.Get("http://127.0.0.1" + addr + "/live")
+	if err != nil {
+		t.Fatalf("server did not serve within its context window: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status=%d, want=%d", resp.StatusCode, http.StatusOK)
+	}
+
+	if err := <-errCh; err != nil && err != ctx.Err() {
+		t.Error(err)
+	}
+}
+
+// TestAuthPortRejectsUnauthenticatedAndAcceptsValidToken drives a Server
+// built with AuthPort end to end, confirming its admin surfaces are
+// unreachable without a bearer token and reachable with a valid one.
+func TestAuthPortRejectsUnauthenticatedAndAcceptsValidToken(t *testing.T) {
+	addr := ":8003"
+	authAddr := ":8004"
+	os.Setenv("ADDRESS", addr)
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Warn("address is already in use, ignore unit test")
+		t.SkipNow()
+		return
+	}
+	l.Close() // close to start the test
+
+	secret := []byte("top-secret")
+	f, err := ioutil.TempFile("", "authport-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(secret); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	srv := server.New("",
+		server.AddressFromEnv(),
+		server.AuthPort(authAddr, f.Name()),
+	)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServeContext(ctx) }()
+	time.Sleep(50 * time.Millisecond)
+
+	url := "http://127.0.0.1" + authAddr + "/debug/vars"
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("auth port did not serve within its context window: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("got status=%d, want=%d for an unauthenticated request", resp.StatusCode, http.StatusUnauthorized)
+	}
+
+	claims := jwt.StandardClaims{IssuedAt: time.Now().Unix()}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status=%d, want=%d for a request with a valid bearer token", resp.StatusCode, http.StatusOK)
+	}
+
+	if err := <-errCh; err != nil && err != ctx.Err() {
+		t.Error(err)
+	}
+}